@@ -0,0 +1,18 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"github.com/tsuru/tsuru/db/storage"
+	"gopkg.in/mgo.v2"
+)
+
+// Snapshots returns the collection used to store volume.Snapshot documents,
+// ensuring the index used to list every snapshot of a given volume.
+func (s *Storage) Snapshots() *storage.Collection {
+	coll := s.Collection("volume_snapshots")
+	coll.EnsureIndex(mgo.Index{Key: []string{"volumename"}})
+	return coll
+}
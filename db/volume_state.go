@@ -0,0 +1,16 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"github.com/tsuru/tsuru/db/storage"
+)
+
+// VolumeStates returns the collection used to store volume.VolumeState
+// documents, keyed by volume name, mirroring how Volumes and VolumeBinds are
+// exposed.
+func (s *Storage) VolumeStates() *storage.Collection {
+	return s.Collection("volume_states")
+}
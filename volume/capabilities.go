@@ -0,0 +1,111 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultFilesystems is used to validate a volume's "filesystem" opt when the
+// plan doesn't declare an explicit Filesystems list.
+var DefaultFilesystems = []string{"ext4", "xfs"}
+
+// PlanCapabilities describes what a volume plan's backend actually supports,
+// parsed out of the plan's otherwise free-form Opts so that invalid volumes
+// can be rejected at create time instead of failing later at mount.
+type PlanCapabilities struct {
+	Filesystems []string `json:"filesystems"`
+	MinSize     string   `json:"minsize"`
+	MaxSize     string   `json:"maxsize"`
+	CRUD        bool     `json:"crud"`
+}
+
+func (p *PlanCapabilities) allowedFilesystems() []string {
+	if len(p.Filesystems) == 0 {
+		return DefaultFilesystems
+	}
+	return p.Filesystems
+}
+
+func (p *PlanCapabilities) validate(opts map[string]string) error {
+	if fs := opts["filesystem"]; fs != "" {
+		allowed := p.allowedFilesystems()
+		var ok bool
+		for _, a := range allowed {
+			if a == fs {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return errors.Errorf("filesystem %q is not allowed by this plan, expected one of %s", fs, strings.Join(allowed, ", "))
+		}
+	}
+	size, err := ActualSize(opts)
+	if err != nil {
+		return err
+	}
+	if p.CRUD && size == 0 {
+		return errors.New("size opt is required for plans backed by a CRUD-capable volume backend")
+	}
+	if p.MinSize != "" && size > 0 {
+		min, err := parseSize(p.MinSize)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if size < min {
+			return errors.Errorf("size %q is smaller than the plan minimum of %s", opts["size"], p.MinSize)
+		}
+	}
+	if p.MaxSize != "" && size > 0 {
+		max, err := parseSize(p.MaxSize)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if size > max {
+			return errors.Errorf("size %q is larger than the plan maximum of %s", opts["size"], p.MaxSize)
+		}
+	}
+	return nil
+}
+
+// ActualSize parses the "size" opt, understanding suffixes like 10G or 500M,
+// and returns the size in bytes. A missing or empty size opt is not an
+// error: it returns 0, letting callers decide whether a size is required.
+func ActualSize(opts map[string]string) (uint64, error) {
+	sizeStr := opts["size"]
+	if sizeStr == "" {
+		return 0, nil
+	}
+	return parseSize(sizeStr)
+}
+
+var sizeSuffixes = map[byte]uint64{
+	'K': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+	'T': 1 << 40,
+}
+
+func parseSize(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	multiplier := uint64(1)
+	numPart := s
+	last := s[len(s)-1]
+	if mult, ok := sizeSuffixes[last]; ok {
+		multiplier = mult
+		numPart = s[:len(s)-1]
+	}
+	n, err := strconv.ParseUint(numPart, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid size %q: %s", s, err)
+	}
+	return n * multiplier, nil
+}
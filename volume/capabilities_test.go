@@ -0,0 +1,92 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import "testing"
+
+func TestActualSize(t *testing.T) {
+	cases := []struct {
+		opts    map[string]string
+		size    uint64
+		wantErr bool
+	}{
+		{opts: map[string]string{}, size: 0},
+		{opts: map[string]string{"size": ""}, size: 0},
+		{opts: map[string]string{"size": "10G"}, size: 10 << 30},
+		{opts: map[string]string{"size": "500M"}, size: 500 << 20},
+		{opts: map[string]string{"size": "1024"}, size: 1024},
+		{opts: map[string]string{"size": "2T"}, size: 2 << 40},
+		{opts: map[string]string{"size": "notanumber"}, wantErr: true},
+		{opts: map[string]string{"size": "10X"}, wantErr: true},
+	}
+	for _, c := range cases {
+		size, err := ActualSize(c.opts)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("ActualSize(%v): expected error, got none", c.opts)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ActualSize(%v): unexpected error: %s", c.opts, err)
+			continue
+		}
+		if size != c.size {
+			t.Errorf("ActualSize(%v): expected %d, got %d", c.opts, c.size, size)
+		}
+	}
+}
+
+func TestPlanCapabilitiesValidateFilesystem(t *testing.T) {
+	p := PlanCapabilities{Filesystems: []string{"ext4", "xfs"}}
+	err := p.validate(map[string]string{"filesystem": "zfs"})
+	if err == nil {
+		t.Error("expected error for disallowed filesystem, got none")
+	}
+	err = p.validate(map[string]string{"filesystem": "xfs"})
+	if err != nil {
+		t.Errorf("unexpected error for allowed filesystem: %s", err)
+	}
+}
+
+func TestPlanCapabilitiesValidateDefaultFilesystems(t *testing.T) {
+	var p PlanCapabilities
+	err := p.validate(map[string]string{"filesystem": "ext4"})
+	if err != nil {
+		t.Errorf("expected ext4 to be allowed by default, got: %s", err)
+	}
+	err = p.validate(map[string]string{"filesystem": "btrfs"})
+	if err == nil {
+		t.Error("expected btrfs to be rejected by default, got no error")
+	}
+}
+
+func TestPlanCapabilitiesValidateCRUDRequiresSize(t *testing.T) {
+	p := PlanCapabilities{CRUD: true}
+	err := p.validate(map[string]string{})
+	if err == nil {
+		t.Error("expected error for missing size on a CRUD-capable plan")
+	}
+	err = p.validate(map[string]string{"size": "10G"})
+	if err != nil {
+		t.Errorf("unexpected error with size set: %s", err)
+	}
+}
+
+func TestPlanCapabilitiesValidateSizeRange(t *testing.T) {
+	p := PlanCapabilities{MinSize: "1G", MaxSize: "10G"}
+	err := p.validate(map[string]string{"size": "500M"})
+	if err == nil {
+		t.Error("expected error for size below the plan minimum")
+	}
+	err = p.validate(map[string]string{"size": "20G"})
+	if err == nil {
+		t.Error("expected error for size above the plan maximum")
+	}
+	err = p.validate(map[string]string{"size": "5G"})
+	if err != nil {
+		t.Errorf("unexpected error for size within range: %s", err)
+	}
+}
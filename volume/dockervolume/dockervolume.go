@@ -0,0 +1,313 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dockervolume exposes tsuru managed volumes over the Docker Volume
+// Plugin protocol, so Docker/Swarm nodes that are not managed by the tsuru
+// provisioner can mount and unmount the same volume definitions tsuru uses
+// internally.
+package dockervolume
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/volume"
+)
+
+const pluginContentType = "application/vnd.docker.plugins.v1.1+json"
+
+// sourcePrefix namespaces the Source tagged onto a VolumeBind by this
+// package, so BindBySource can't accidentally match a bind some other
+// integration tagged with a Docker volume name that collides with this
+// plugin's.
+const sourcePrefix = "dockervolume:"
+
+var ErrMissingAppOpt = errors.New("volume opts must include \"app\" and \"mountpoint\"")
+
+// bindSource returns the Source value this plugin tags the bind it creates
+// for the Docker volume named name with, so VolumeDriver.Remove/Mount/
+// Unmount can find that exact bind again via volume.BindBySource. The
+// plugin protocol only carries the volume name (and a container ID with no
+// app semantics) on those calls, and persisting the association in Mongo
+// this way means it survives a plugin restart, unlike process-local state.
+func bindSource(name string) string {
+	return sourcePrefix + name
+}
+
+// listScope restricts VolumeDriver.List to whatever this plugin instance is
+// configured to expose; see NewServer.
+var listScope volume.Filter
+
+type createRequest struct {
+	Name string
+	Opts map[string]string
+}
+
+type removeRequest struct {
+	Name string
+}
+
+type mountRequest struct {
+	Name string
+	ID   string
+}
+
+type pathRequest struct {
+	Name string
+}
+
+type capabilitiesResponse struct {
+	Capabilities capabilities `json:"Capabilities"`
+}
+
+type capabilities struct {
+	Scope string `json:"Scope"`
+}
+
+type volumeInfo struct {
+	Name       string
+	Mountpoint string `json:",omitempty"`
+}
+
+type getResponse struct {
+	Volume volumeInfo
+	Err    string `json:",omitempty"`
+}
+
+type listResponse struct {
+	Volumes []volumeInfo
+	Err     string `json:",omitempty"`
+}
+
+type mountResponse struct {
+	Mountpoint string
+	Err        string `json:",omitempty"`
+}
+
+type errResponse struct {
+	Err string `json:",omitempty"`
+}
+
+// NewServer returns an http.Server that answers the Docker Volume Plugin
+// protocol (Plugin.Activate and the VolumeDriver.* endpoints) and can be
+// served over a Unix socket with ListenAndServe. scope restricts
+// VolumeDriver.List to the volumes matching it (e.g. the pool this plugin
+// instance's node belongs to), since the socket has no notion of the tsuru
+// user or team making the request.
+func NewServer(scope volume.Filter) *http.Server {
+	listScope = scope
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Plugin.Activate", handleActivate)
+	mux.HandleFunc("/VolumeDriver.Create", handleCreate)
+	mux.HandleFunc("/VolumeDriver.Remove", handleRemove)
+	mux.HandleFunc("/VolumeDriver.Mount", handleMount)
+	mux.HandleFunc("/VolumeDriver.Unmount", handleUnmount)
+	mux.HandleFunc("/VolumeDriver.Path", handlePath)
+	mux.HandleFunc("/VolumeDriver.Get", handleGet)
+	mux.HandleFunc("/VolumeDriver.List", handleList)
+	mux.HandleFunc("/VolumeDriver.Capabilities", handleCapabilities)
+	return &http.Server{Handler: mux}
+}
+
+// ListenAndServe creates the Unix socket at socketPath and starts serving the
+// plugin protocol on it, scoped to scope, removing any stale socket left
+// behind by a previous run.
+func ListenAndServe(socketPath string, scope volume.Filter) error {
+	os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return NewServer(scope).Serve(listener)
+}
+
+func handleActivate(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, struct {
+		Implements []string
+	}{Implements: []string{"VolumeDriver"}})
+}
+
+func handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	appName, mountPoint, ok := appAndMountPoint(req.Opts)
+	if !ok {
+		writeErr(w, ErrMissingAppOpt)
+		return
+	}
+	v, err := volume.Load(req.Name)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	err = v.BindApp(appName, mountPoint, volume.BindModeReadWrite)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	err = v.SetBindSource(appName, mountPoint, bindSource(req.Name))
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeErr(w, nil)
+}
+
+func handleRemove(w http.ResponseWriter, r *http.Request) {
+	var req removeRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	bind, err := volume.BindBySource(bindSource(req.Name))
+	if err == volume.ErrVolumeBindNotFound {
+		writeErr(w, nil)
+		return
+	}
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	v, err := volume.Load(bind.ID.Volume)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	err = v.UnbindApp(bind.ID.App, bind.ID.MountPoint)
+	if err != nil && err != volume.ErrVolumeBindNotFound {
+		writeErr(w, err)
+		return
+	}
+	writeErr(w, nil)
+}
+
+func handleMount(w http.ResponseWriter, r *http.Request) {
+	var req mountRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	bind, err := volume.BindBySource(bindSource(req.Name))
+	if err != nil {
+		writeJSON(w, mountResponse{Err: err.Error()})
+		return
+	}
+	writeJSON(w, mountResponse{Mountpoint: mountpointOf(*bind)})
+}
+
+func handleUnmount(w http.ResponseWriter, r *http.Request) {
+	var req mountRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	bind, err := volume.BindBySource(bindSource(req.Name))
+	if err == volume.ErrVolumeBindNotFound {
+		writeErr(w, nil)
+		return
+	}
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	v, err := volume.Load(bind.ID.Volume)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	err = v.UnbindApp(bind.ID.App, bind.ID.MountPoint)
+	if err != nil && err != volume.ErrVolumeBindNotFound {
+		writeErr(w, err)
+		return
+	}
+	writeErr(w, nil)
+}
+
+func handlePath(w http.ResponseWriter, r *http.Request) {
+	var req pathRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	v, err := volume.Load(req.Name)
+	if err != nil {
+		writeJSON(w, mountResponse{Err: err.Error()})
+		return
+	}
+	binds, err := v.Binds()
+	if err != nil || len(binds) == 0 {
+		writeJSON(w, mountResponse{})
+		return
+	}
+	writeJSON(w, mountResponse{Mountpoint: mountpointOf(binds[0])})
+}
+
+func handleGet(w http.ResponseWriter, r *http.Request) {
+	var req pathRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	v, err := volume.Load(req.Name)
+	if err != nil {
+		writeJSON(w, getResponse{Err: err.Error()})
+		return
+	}
+	writeJSON(w, getResponse{Volume: volumeInfo{Name: v.Name}})
+}
+
+func handleList(w http.ResponseWriter, r *http.Request) {
+	volumes, err := volume.List(listScope)
+	if err != nil {
+		writeJSON(w, listResponse{Err: err.Error()})
+		return
+	}
+	infos := make([]volumeInfo, len(volumes))
+	for i, v := range volumes {
+		infos[i] = volumeInfo{Name: v.Name}
+	}
+	writeJSON(w, listResponse{Volumes: infos})
+}
+
+func handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, capabilitiesResponse{Capabilities: capabilities{Scope: "global"}})
+}
+
+// mountpointOf returns the path to hand back to Docker for bind: the path
+// the Driver actually mounted, falling back to the bind-time mountpoint opt
+// for volumes with no registered Driver.
+func mountpointOf(bind volume.VolumeBind) string {
+	if bind.HostPath != "" {
+		return bind.HostPath
+	}
+	return bind.ID.MountPoint
+}
+
+func appAndMountPoint(opts map[string]string) (app, mountPoint string, ok bool) {
+	app = opts["app"]
+	mountPoint = opts["mountpoint"]
+	return app, mountPoint, app != "" && mountPoint != ""
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		writeErr(w, errors.WithStack(err))
+		return false
+	}
+	return true
+}
+
+func writeErr(w http.ResponseWriter, err error) {
+	resp := errResponse{}
+	if err != nil {
+		resp.Err = err.Error()
+	}
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", pluginContentType)
+	json.NewEncoder(w).Encode(v)
+}
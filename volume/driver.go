@@ -0,0 +1,81 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Capabilities describes what a Driver supports, mirroring the subset of the
+// Docker Volume Plugin capabilities tsuru cares about.
+type Capabilities struct {
+	Scope string
+}
+
+// Driver knows how to provision and attach volumes for a given backend (e.g.
+// NFS, tmpfs, a cloud block storage service). Registering a Driver moves
+// that knowledge out of the provisioner and into the volume plan itself.
+type Driver interface {
+	Create(v *Volume) error
+	Remove(v *Volume) error
+	Mount(v *Volume, bind VolumeBind) (string, error)
+	Unmount(v *Volume, bind VolumeBind) error
+	Capabilities() Capabilities
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]Driver)
+)
+
+// Register makes a Driver available under name, for use by drivers
+// registering themselves from an init function. It panics if Register is
+// called twice with the same name or if d is nil.
+func Register(name string, d Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if d == nil {
+		panic("volume: Register driver is nil")
+	}
+	if _, dup := drivers[name]; dup {
+		panic("volume: Register called twice for driver " + name)
+	}
+	drivers[name] = d
+}
+
+// GetDriver returns the Driver registered under name, or an error if none was
+// registered.
+func GetDriver(name string) (Driver, error) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	d, ok := drivers[name]
+	if !ok {
+		return nil, fmt.Errorf("volume: unknown driver %q", name)
+	}
+	return d, nil
+}
+
+// driverName returns the name of the driver that should handle v: an
+// explicit Volume.Driver takes precedence, falling back to the volume plan
+// name for backwards compatibility with plans that were never updated to set
+// Driver explicitly.
+func (v *Volume) driverName() string {
+	if v.Driver != "" {
+		return v.Driver
+	}
+	return v.Plan.Name
+}
+
+// driver returns the Driver that should handle v, if any is registered. A
+// volume whose plan/driver name has no registered Driver is handled entirely
+// by the provisioner, as before drivers existed.
+func (v *Volume) driver() (Driver, bool) {
+	d, err := GetDriver(v.driverName())
+	if err != nil {
+		return nil, false
+	}
+	return d, true
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import "testing"
+
+type fakeDriver struct{}
+
+func (fakeDriver) Create(v *Volume) error                          { return nil }
+func (fakeDriver) Remove(v *Volume) error                           { return nil }
+func (fakeDriver) Mount(v *Volume, bind VolumeBind) (string, error) { return "", nil }
+func (fakeDriver) Unmount(v *Volume, bind VolumeBind) error         { return nil }
+func (fakeDriver) Capabilities() Capabilities                       { return Capabilities{} }
+
+func TestRegisterAndGetDriver(t *testing.T) {
+	name := "driver-test-register"
+	Register(name, fakeDriver{})
+	d, err := GetDriver(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := d.(fakeDriver); !ok {
+		t.Errorf("expected the registered fakeDriver, got %T", d)
+	}
+}
+
+func TestRegisterPanicsOnNilDriver(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Register(nil) to panic")
+		}
+	}()
+	Register("driver-test-nil", nil)
+}
+
+func TestRegisterPanicsOnDuplicateName(t *testing.T) {
+	name := "driver-test-duplicate"
+	Register(name, fakeDriver{})
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering the same name twice to panic")
+		}
+	}()
+	Register(name, fakeDriver{})
+}
+
+func TestGetDriverUnknown(t *testing.T) {
+	_, err := GetDriver("driver-test-unknown")
+	if err == nil {
+		t.Error("expected an error for an unregistered driver name")
+	}
+}
+
+func TestVolumeDriverName(t *testing.T) {
+	v := Volume{Plan: VolumePlan{Name: "myplan"}}
+	if v.driverName() != "myplan" {
+		t.Errorf("expected driverName to fall back to the plan name, got %q", v.driverName())
+	}
+	v.Driver = "explicit-driver"
+	if v.driverName() != "explicit-driver" {
+		t.Errorf("expected an explicit Driver to take precedence, got %q", v.driverName())
+	}
+}
+
+func TestVolumeDriverFallback(t *testing.T) {
+	name := "driver-test-fallback"
+	Register(name, fakeDriver{})
+	v := Volume{Plan: VolumePlan{Name: name}}
+	d, ok := v.driver()
+	if !ok {
+		t.Fatal("expected a driver to be found for a registered plan name")
+	}
+	if _, ok := d.(fakeDriver); !ok {
+		t.Errorf("expected the registered fakeDriver, got %T", d)
+	}
+	v = Volume{Plan: VolumePlan{Name: "driver-test-no-such-driver"}}
+	_, ok = v.driver()
+	if ok {
+		t.Error("expected no driver for a plan name with nothing registered")
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package drivers ships the in-tree volume.Driver implementations: local,
+// nfs and tmpfs. External code can register additional drivers (e.g. for
+// Rook/Ceph or EBS) with volume.Register without touching this package.
+package drivers
+
+import (
+	"fmt"
+
+	"github.com/tsuru/tsuru/volume"
+)
+
+// LocalDriverName is the name this driver registers under. Volumes must set
+// Volume.Driver to this value explicitly to use it; it is not tied to any
+// plan name, so existing plans (which may happen to be named "local") keep
+// working unaffected unless they opt in.
+const LocalDriverName = "tsuru-local"
+
+func init() {
+	volume.Register(LocalDriverName, localDriver{})
+}
+
+// localDriver creates and removes nothing. Mount reports
+// /var/lib/tsuru/volumes/<volume name> as the bind's host path and performs
+// no mount itself; the bind mount is left to the provisioner.
+type localDriver struct{}
+
+func (localDriver) Create(v *volume.Volume) error { return nil }
+
+func (localDriver) Remove(v *volume.Volume) error { return nil }
+
+func (localDriver) Mount(v *volume.Volume, bind volume.VolumeBind) (string, error) {
+	return fmt.Sprintf("/var/lib/tsuru/volumes/%s", v.Name), nil
+}
+
+func (localDriver) Unmount(v *volume.Volume, bind volume.VolumeBind) error { return nil }
+
+func (localDriver) Capabilities() volume.Capabilities {
+	return volume.Capabilities{Scope: "local"}
+}
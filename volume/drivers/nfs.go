@@ -0,0 +1,47 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package drivers
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/volume"
+)
+
+// NFSDriverName is the name this driver registers under. Volumes must set
+// Volume.Driver to this value explicitly to use it; it is not tied to any
+// plan name, so existing plans (which may happen to be named "nfs") keep
+// working unaffected unless they opt in.
+const NFSDriverName = "tsuru-nfs"
+
+func init() {
+	volume.Register(NFSDriverName, nfsDriver{})
+}
+
+// nfsDriver refers to an existing NFS export described by the volume's Opts
+// ("server" and "export"); Create only checks that both are set. Mount
+// returns "server:export" as the bind's host path and performs no mount
+// itself, and Unmount does nothing. The export is never provisioned.
+type nfsDriver struct{}
+
+func (nfsDriver) Create(v *volume.Volume) error {
+	if v.Opts["server"] == "" || v.Opts["export"] == "" {
+		return errors.New("nfs driver requires \"server\" and \"export\" opts")
+	}
+	return nil
+}
+
+func (nfsDriver) Remove(v *volume.Volume) error { return nil }
+
+func (nfsDriver) Mount(v *volume.Volume, bind volume.VolumeBind) (string, error) {
+	return fmt.Sprintf("%s:%s", v.Opts["server"], v.Opts["export"]), nil
+}
+
+func (nfsDriver) Unmount(v *volume.Volume, bind volume.VolumeBind) error { return nil }
+
+func (nfsDriver) Capabilities() volume.Capabilities {
+	return volume.Capabilities{Scope: "global"}
+}
@@ -0,0 +1,40 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package drivers
+
+import (
+	"fmt"
+
+	"github.com/tsuru/tsuru/volume"
+)
+
+// TmpfsDriverName is the name this driver registers under. Volumes must set
+// Volume.Driver to this value explicitly to use it; it is not tied to any
+// plan name, so existing plans (which may happen to be named "tmpfs") keep
+// working unaffected unless they opt in.
+const TmpfsDriverName = "tsuru-tmpfs"
+
+func init() {
+	volume.Register(TmpfsDriverName, tmpfsDriver{})
+}
+
+// tmpfsDriver reports Scope "local" and creates and removes nothing.
+// Mount reports /tmp/tsuru-volumes/<volume name> as the bind's host path and
+// performs no mount itself; no tmpfs is created, and Unmount does nothing.
+type tmpfsDriver struct{}
+
+func (tmpfsDriver) Create(v *volume.Volume) error { return nil }
+
+func (tmpfsDriver) Remove(v *volume.Volume) error { return nil }
+
+func (tmpfsDriver) Mount(v *volume.Volume, bind volume.VolumeBind) (string, error) {
+	return fmt.Sprintf("/tmp/tsuru-volumes/%s", v.Name), nil
+}
+
+func (tmpfsDriver) Unmount(v *volume.Volume, bind volume.VolumeBind) error { return nil }
+
+func (tmpfsDriver) Capabilities() volume.Capabilities {
+	return volume.Capabilities{Scope: "local"}
+}
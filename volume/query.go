@@ -0,0 +1,106 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var ErrAmbiguousVolumeName = errors.New("volume name is ambiguous")
+
+// Filter narrows down the result of List. Zero-valued fields are ignored.
+type Filter struct {
+	TeamOwner string
+	Pool      string
+	PlanName  string
+	Status    string
+	BoundApp  string
+}
+
+func (f Filter) query() (bson.M, error) {
+	query := bson.M{}
+	if f.TeamOwner != "" {
+		query["teamowner"] = f.TeamOwner
+	}
+	if f.Pool != "" {
+		query["pool"] = f.Pool
+	}
+	if f.PlanName != "" {
+		query["plan.name"] = f.PlanName
+	}
+	if f.Status != "" {
+		query["status"] = f.Status
+	}
+	if f.BoundApp != "" {
+		names, err := boundVolumeNames(f.BoundApp)
+		if err != nil {
+			return nil, err
+		}
+		query["_id"] = bson.M{"$in": names}
+	}
+	return query, nil
+}
+
+func boundVolumeNames(appName string) ([]string, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	var names []string
+	err = conn.VolumeBinds().Find(bson.M{"_id.app": appName}).Distinct("_id.volume", &names)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return names, nil
+}
+
+// List returns every Volume matching filter.
+func List(filter Filter) ([]Volume, error) {
+	query, err := filter.query()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	var volumes []Volume
+	err = conn.Volumes().Find(query).All(&volumes)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return volumes, nil
+}
+
+// LookupVolume returns the single volume whose name has prefix as a unique
+// prefix. It fails with ErrVolumeNotFound if no volume matches and
+// ErrAmbiguousVolumeName if more than one does, so callers like the HTTP API
+// or the tsuru client can let users refer to a volume without knowing its
+// full name.
+func LookupVolume(prefix string) (*Volume, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	var volumes []Volume
+	err = conn.Volumes().Find(bson.M{"_id": bson.RegEx{Pattern: "^" + regexp.QuoteMeta(prefix)}}).All(&volumes)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(volumes) == 0 {
+		return nil, ErrVolumeNotFound
+	}
+	if len(volumes) > 1 {
+		return nil, ErrAmbiguousVolumeName
+	}
+	return &volumes[0], nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestFilterQueryEmpty(t *testing.T) {
+	query, err := Filter{}.query()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(query) != 0 {
+		t.Errorf("expected an empty query matching every volume, got %v", query)
+	}
+}
+
+func TestFilterQueryFields(t *testing.T) {
+	filter := Filter{TeamOwner: "myteam", Pool: "mypool", PlanName: "myplan", Status: "ready"}
+	query, err := filter.query()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := bson.M{
+		"teamowner": "myteam",
+		"pool":      "mypool",
+		"plan.name": "myplan",
+		"status":    "ready",
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+}
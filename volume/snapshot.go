@@ -0,0 +1,194 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var (
+	ErrSnapshotNotFound     = errors.New("snapshot not found")
+	ErrVolumeBoundReadWrite = errors.New("refusing to snapshot a volume with an active read-write bind, use force to override")
+)
+
+// Snapshot is an immutable point-in-time record of a Volume, created and
+// restored through the volume's Driver. Snapshots are never updated once
+// created; RestoreSnapshot only ever reads them.
+type Snapshot struct {
+	Name       string `bson:"_id"`
+	VolumeName string
+	CreatedAt  time.Time
+	Opts       map[string]string `bson:",omitempty"`
+	DriverRef  string            `bson:",omitempty"`
+}
+
+// SnapshotDriver is implemented by Drivers whose backend supports
+// snapshotting. It's kept separate from Driver so that backends without
+// snapshot support (e.g. tmpfs) aren't forced to implement it.
+type SnapshotDriver interface {
+	Snapshot(v *Volume, snapshotName string) (driverRef string, err error)
+	RestoreSnapshot(v *Volume, snapshotName, driverRef string) error
+	RemoveSnapshot(v *Volume, snapshotName, driverRef string) error
+	Clone(v *Volume, newName string) error
+}
+
+func (v *Volume) snapshotDriver() (SnapshotDriver, error) {
+	d, ok := v.driver()
+	if !ok {
+		return nil, errors.Errorf("no driver registered for volume %q", v.Name)
+	}
+	sd, ok := d.(SnapshotDriver)
+	if !ok {
+		return nil, errors.Errorf("driver for volume %q does not support snapshots", v.Name)
+	}
+	return sd, nil
+}
+
+func (v *Volume) hasReadWriteBind() (bool, error) {
+	binds, err := v.Binds()
+	if err != nil {
+		return false, err
+	}
+	for _, b := range binds {
+		if b.Mode == BindModeReadWrite {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SnapshotOptions controls how Volume.Snapshot behaves. It's a struct,
+// rather than Snapshot taking a bare force bool, so that the rw-bind safety
+// check it controls is visible from the call site.
+type SnapshotOptions struct {
+	// Force allows snapshotting a volume that has an active read-write
+	// bind, bypassing the safety check that otherwise rejects it.
+	Force bool
+}
+
+// Snapshot creates an immutable Snapshot of v named name. It refuses to
+// snapshot a volume that has an active read-write bind unless
+// opts.Force is true, since the backend's point-in-time copy may otherwise
+// be inconsistent.
+func (v *Volume) Snapshot(name string, opts SnapshotOptions) (*Snapshot, error) {
+	if !opts.Force {
+		rw, err := v.hasReadWriteBind()
+		if err != nil {
+			return nil, err
+		}
+		if rw {
+			return nil, ErrVolumeBoundReadWrite
+		}
+	}
+	sd, err := v.snapshotDriver()
+	if err != nil {
+		return nil, err
+	}
+	driverRef, err := sd.Snapshot(v, name)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	snap := Snapshot{
+		Name:       name,
+		VolumeName: v.Name,
+		CreatedAt:  time.Now().UTC(),
+		Opts:       v.Opts,
+		DriverRef:  driverRef,
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	err = conn.Snapshots().Insert(snap)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &snap, nil
+}
+
+// ListSnapshots returns every Snapshot taken of v.
+func (v *Volume) ListSnapshots() ([]Snapshot, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	var snapshots []Snapshot
+	err = conn.Snapshots().Find(bson.M{"volumename": v.Name}).All(&snapshots)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return snapshots, nil
+}
+
+func (v *Volume) loadSnapshot(name string) (*Snapshot, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	var snap Snapshot
+	err = conn.Snapshots().FindId(name).One(&snap)
+	if err == mgo.ErrNotFound {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &snap, nil
+}
+
+// RestoreSnapshot restores v's backend to the state recorded in the named
+// snapshot.
+func (v *Volume) RestoreSnapshot(name string) error {
+	snap, err := v.loadSnapshot(name)
+	if err != nil {
+		return err
+	}
+	sd, err := v.snapshotDriver()
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(sd.RestoreSnapshot(v, snap.Name, snap.DriverRef))
+}
+
+// Clone creates a new Volume named newName backed by a driver-level copy of
+// v's current data. The returned Volume is saved and ready to be bound to
+// apps. The clone's Mongo document is reserved before the driver-level copy
+// is made and removed again if that copy fails, so a failed Clone never
+// leaves an orphaned backend clone with no tsuru record of it.
+func (v *Volume) Clone(newName string) (*Volume, error) {
+	sd, err := v.snapshotDriver()
+	if err != nil {
+		return nil, err
+	}
+	clone := *v
+	clone.Name = newName
+	err = clone.Validate()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	err = conn.Volumes().Insert(&clone)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = sd.Clone(v, newName)
+	if err != nil {
+		conn.Volumes().RemoveId(clone.Name)
+		return nil, errors.WithStack(err)
+	}
+	return &clone, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package volume
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// VolumeState holds mutable, frequently updated data about a Volume that is
+// kept in a separate collection so that Volume updates don't race with the
+// bookkeeping the provisioner integration does on every mount/unmount.
+type VolumeState struct {
+	VolumeName    string `bson:"_id"`
+	MountCount    int
+	NeedsCopyUp   bool
+	LastMountedAt time.Time
+}
+
+func loadState(volumeName string) (*VolumeState, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	var s VolumeState
+	err = conn.VolumeStates().FindId(volumeName).One(&s)
+	if err == mgo.ErrNotFound {
+		return &VolumeState{VolumeName: volumeName}, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &s, nil
+}
+
+// IncrementMountCount atomically increases the volume's mount counter and
+// records the time of the mount, creating the state document if it doesn't
+// exist yet.
+func (v *Volume) IncrementMountCount() error {
+	conn, err := db.Conn()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer conn.Close()
+	_, err = conn.VolumeStates().UpsertId(v.Name, bson.M{
+		"$inc": bson.M{"mountcount": 1},
+		"$set": bson.M{"lastmountedat": time.Now().UTC()},
+	})
+	return errors.WithStack(err)
+}
+
+// DecrementMountCount atomically decreases the volume's mount counter. It is
+// a no-op if no state document exists or the counter is already at zero,
+// mirroring how a missing document is treated as count=0 and guaranteeing
+// the counter never goes negative.
+func (v *Volume) DecrementMountCount() error {
+	conn, err := db.Conn()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer conn.Close()
+	err = conn.VolumeStates().Update(bson.M{
+		"_id":        v.Name,
+		"mountcount": bson.M{"$gt": 0},
+	}, bson.M{
+		"$inc": bson.M{"mountcount": -1},
+	})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return errors.WithStack(err)
+}
+
+// State returns the volume's current mutable state, treating a missing
+// document as a zero-valued state with MountCount 0.
+func (v *Volume) State() (*VolumeState, error) {
+	return loadState(v.Name)
+}
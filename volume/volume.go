@@ -45,6 +45,15 @@ type VolumeBindID struct {
 type VolumeBind struct {
 	ID   VolumeBindID `bson:"_id"`
 	Mode BindMode
+	// HostPath is the path the Driver actually mounted for this bind, as
+	// returned by Driver.Mount. It's empty for volumes with no registered
+	// Driver, where the provisioner decides the path on its own.
+	HostPath string `bson:",omitempty"`
+	// Source identifies whatever external system created this bind on
+	// tsuru's behalf (e.g. a Docker Volume Plugin volume name), so that
+	// system can find this exact bind again via BindBySource instead of
+	// keeping its own state.
+	Source string `bson:",omitempty"`
 }
 
 type Volume struct {
@@ -53,6 +62,7 @@ type Volume struct {
 	Plan      VolumePlan
 	TeamOwner string
 	Status    string
+	Driver    string            `bson:",omitempty"`
 	Opts      map[string]string `bson:",omitempty"`
 }
 
@@ -89,9 +99,18 @@ func (v *Volume) Validate() error {
 		return errors.Errorf("invalid type for plan opts %T", planOpts)
 	}
 	v.Plan.Opts = planOpts
-	return nil
+	var capabilities PlanCapabilities
+	err = v.UnmarshalPlan(&capabilities)
+	if err != nil {
+		return err
+	}
+	return capabilities.validate(v.Opts)
 }
 
+// Save validates and persists v, reserving its Mongo document before asking
+// any registered Driver to create the backing resource. If the driver call
+// fails on a brand new volume, the reservation is rolled back so a failed
+// Save never leaves a backend resource with no tsuru record of it.
 func (v *Volume) Save() error {
 	err := v.Validate()
 	if err != nil {
@@ -102,8 +121,37 @@ func (v *Volume) Save() error {
 		return errors.WithStack(err)
 	}
 	defer conn.Close()
-	_, err = conn.Volumes().UpsertId(v.Name, v)
-	return errors.WithStack(err)
+	changeInfo, err := conn.Volumes().UpsertId(v.Name, v)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if d, ok := v.driver(); ok {
+		err = d.Create(v)
+		if err != nil {
+			if changeInfo != nil && changeInfo.UpsertedId != nil {
+				conn.Volumes().RemoveId(v.Name)
+			}
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// Remove deletes the volume document and, if a Driver is registered for it,
+// asks the driver to tear down the underlying backend.
+func (v *Volume) Remove() error {
+	conn, err := db.Conn()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer conn.Close()
+	if d, ok := v.driver(); ok {
+		err = d.Remove(v)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(conn.Volumes().RemoveId(v.Name))
 }
 
 func (v *Volume) BindApp(appName, mountPoint string, mode BindMode) error {
@@ -127,10 +175,26 @@ func (v *Volume) BindApp(appName, mountPoint string, mode BindMode) error {
 		Mode: mode,
 	}
 	err = conn.VolumeBinds().Insert(bind)
-	if err != nil && mgo.IsDup(err) {
-		return ErrVolumeAlreadyBound
+	if err != nil {
+		if mgo.IsDup(err) {
+			return ErrVolumeAlreadyBound
+		}
+		return errors.WithStack(err)
 	}
-	return errors.WithStack(err)
+	if d, ok := v.driver(); ok {
+		var hostPath string
+		hostPath, err = d.Mount(v, bind)
+		if err != nil {
+			conn.VolumeBinds().RemoveId(bind.ID)
+			return errors.WithStack(err)
+		}
+		err = conn.VolumeBinds().UpdateId(bind.ID, bson.M{"$set": bson.M{"hostpath": hostPath}})
+		if err != nil {
+			conn.VolumeBinds().RemoveId(bind.ID)
+			return errors.WithStack(err)
+		}
+	}
+	return v.IncrementMountCount()
 }
 
 func (v *Volume) UnbindApp(appName, mountPoint string) error {
@@ -139,15 +203,30 @@ func (v *Volume) UnbindApp(appName, mountPoint string) error {
 		return errors.WithStack(err)
 	}
 	defer conn.Close()
-	err = conn.VolumeBinds().RemoveId(VolumeBindID{
+	bindID := VolumeBindID{
 		App:        appName,
 		Volume:     v.Name,
 		MountPoint: mountPoint,
-	})
+	}
+	var bind VolumeBind
+	err = conn.VolumeBinds().FindId(bindID).One(&bind)
 	if err == mgo.ErrNotFound {
 		return ErrVolumeBindNotFound
 	}
-	return errors.WithStack(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if d, ok := v.driver(); ok {
+		err = d.Unmount(v, bind)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	err = conn.VolumeBinds().RemoveId(bindID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return v.DecrementMountCount()
 }
 
 func (v *Volume) Binds() ([]VolumeBind, error) {
@@ -164,23 +243,53 @@ func (v *Volume) Binds() ([]VolumeBind, error) {
 	return binds, nil
 }
 
-func ListByApp(appName string) ([]Volume, error) {
+// SetBindSource records source as the external identifier for the bind
+// identified by appName/mountPoint on v, so it can later be found again with
+// BindBySource. It's meant for callers, like the Docker Volume Plugin
+// integration, that only learn of an opaque external name after the bind
+// already exists and need a durable way to map that name back to the bind.
+func (v *Volume) SetBindSource(appName, mountPoint, source string) error {
 	conn, err := db.Conn()
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return errors.WithStack(err)
 	}
 	defer conn.Close()
-	var volumeNames []string
-	err = conn.VolumeBinds().Find(bson.M{"_id.app": appName}).Distinct("_id.volume", &volumeNames)
+	bindID := VolumeBindID{
+		App:        appName,
+		Volume:     v.Name,
+		MountPoint: mountPoint,
+	}
+	err = conn.VolumeBinds().UpdateId(bindID, bson.M{"$set": bson.M{"source": source}})
+	if err == mgo.ErrNotFound {
+		return ErrVolumeBindNotFound
+	}
+	return errors.WithStack(err)
+}
+
+// BindBySource returns the VolumeBind previously tagged with source via
+// SetBindSource. It exists so integrations that only carry an opaque
+// external name (e.g. a Docker volume name) on most of their protocol calls
+// can still find the exact bind they created, without keeping their own
+// process-local state that wouldn't survive a restart.
+func BindBySource(source string) (*VolumeBind, error) {
+	conn, err := db.Conn()
 	if err != nil {
-		return nil, err
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	var bind VolumeBind
+	err = conn.VolumeBinds().Find(bson.M{"source": source}).One(&bind)
+	if err == mgo.ErrNotFound {
+		return nil, ErrVolumeBindNotFound
 	}
-	var volumes []Volume
-	err = conn.Volumes().Find(bson.M{"_id": bson.M{"$in": volumeNames}}).All(&volumes)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	return volumes, nil
+	return &bind, nil
+}
+
+func ListByApp(appName string) ([]Volume, error) {
+	return List(Filter{BoundApp: appName})
 }
 
 func Load(name string) (*Volume, error) {